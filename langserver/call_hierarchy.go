@@ -0,0 +1,350 @@
+package langserver
+
+import (
+	"context"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"github.com/sourcegraph/go-langserver/pkg/lsp"
+	"github.com/sourcegraph/jsonrpc2"
+	"golang.org/x/tools/go/loader"
+)
+
+// handleTextDocumentPrepareCallHierarchy resolves the *types.Func enclosing
+// params.Position and returns it as the single root of a call hierarchy.
+func (h *LangHandler) handleTextDocumentPrepareCallHierarchy(ctx context.Context, conn jsonrpc2.JSONRPC2, req *jsonrpc2.Request, params lsp.TextDocumentPositionParams) ([]lsp.CallHierarchyItem, error) {
+	fset, _, nodes, program, pkg, err := h.typecheck(ctx, conn, DocumentURI(params.TextDocument.URI), params.Position)
+	if err != nil {
+		if _, ok := err.(*invalidNodeError); !ok {
+			return nil, err
+		}
+	}
+
+	fn, decl := enclosingFunc(program, pkg, nodes)
+	if fn == nil {
+		return nil, nil
+	}
+
+	return []lsp.CallHierarchyItem{callHierarchyItem(fset, fn, decl)}, nil
+}
+
+// handleCallHierarchyIncomingCalls reports every call site, in any package
+// loaded in the program, whose resolved callee is params.Item.
+func (h *LangHandler) handleCallHierarchyIncomingCalls(ctx context.Context, conn jsonrpc2.JSONRPC2, req *jsonrpc2.Request, params lsp.CallHierarchyIncomingCallsParams) ([]lsp.CallHierarchyIncomingCall, error) {
+	fset, program, pkg, target, _, err := h.resolveCallHierarchyItem(ctx, conn, params.Item)
+	if err != nil || target == nil {
+		return nil, err
+	}
+
+	// When the target is an interface method, also match every concrete
+	// implementation of it, so that calls made directly on an implementing
+	// type are reported too.
+	targets := callHierarchyTargets(program, target)
+
+	var order []*callSite
+	byDecl := map[*ast.FuncDecl]*callSite{}
+
+	for _, info := range program.AllPackages {
+		for _, file := range info.Files {
+			ast.Inspect(file, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				callee := calleeObject(program, info, call)
+				if callee == nil || !containsObject(targets, callee) {
+					return true
+				}
+				caller := enclosingFuncDecl(program, call)
+				if caller == nil {
+					return true
+				}
+				site, ok := byDecl[caller]
+				if !ok {
+					site = &callSite{decl: caller, info: info}
+					byDecl[caller] = site
+					order = append(order, site)
+				}
+				site.calls = append(site.calls, call)
+				return true
+			})
+		}
+	}
+
+	calls := make([]lsp.CallHierarchyIncomingCall, 0, len(order))
+	for _, site := range order {
+		callerObj, ok := site.info.ObjectOf(site.decl.Name).(*types.Func)
+		if !ok {
+			continue
+		}
+		ranges := make([]lsp.Range, len(site.calls))
+		for i, call := range site.calls {
+			ranges[i] = rangeForNode(fset, call)
+		}
+		calls = append(calls, lsp.CallHierarchyIncomingCall{
+			From:       callHierarchyItem(fset, callerObj, site.decl),
+			FromRanges: ranges,
+		})
+	}
+	return calls, nil
+}
+
+// handleCallHierarchyOutgoingCalls reports every call to a named function or
+// method made from the body of the function identified by params.Item. Calls
+// through function values and calls to built-ins are not reported.
+func (h *LangHandler) handleCallHierarchyOutgoingCalls(ctx context.Context, conn jsonrpc2.JSONRPC2, req *jsonrpc2.Request, params lsp.CallHierarchyOutgoingCallsParams) ([]lsp.CallHierarchyOutgoingCall, error) {
+	fset, program, pkg, _, decl, err := h.resolveCallHierarchyItem(ctx, conn, params.Item)
+	if err != nil || decl == nil {
+		return nil, err
+	}
+
+	var order []types.Object
+	callsByCallee := map[types.Object][]ast.Node{}
+
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		callee := calleeObject(program, pkg, call)
+		if callee == nil {
+			return true
+		}
+		if _, ok := callsByCallee[callee]; !ok {
+			order = append(order, callee)
+		}
+		callsByCallee[callee] = append(callsByCallee[callee], call)
+		return true
+	})
+
+	calls := make([]lsp.CallHierarchyOutgoingCall, 0, len(order))
+	for _, callee := range order {
+		fn, ok := callee.(*types.Func)
+		if !ok {
+			continue
+		}
+		// calleeDecl is nil for an interface method; callHierarchyItem
+		// handles that. Only a callee with no source position is skipped.
+		calleeDecl := funcDeclForObject(program, fn)
+		if calleeDecl == nil && !fn.Pos().IsValid() {
+			continue
+		}
+		nodes := callsByCallee[callee]
+		ranges := make([]lsp.Range, len(nodes))
+		for i, call := range nodes {
+			ranges[i] = rangeForNode(fset, call)
+		}
+		calls = append(calls, lsp.CallHierarchyOutgoingCall{
+			To:         callHierarchyItem(fset, fn, calleeDecl),
+			FromRanges: ranges,
+		})
+	}
+	return calls, nil
+}
+
+// callSite groups the call expressions inside decl whose callee is one of
+// the targets being looked up.
+type callSite struct {
+	decl  *ast.FuncDecl
+	info  *loader.PackageInfo
+	calls []ast.Node
+}
+
+// resolveCallHierarchyItem re-typechecks the file named by item.URI and
+// resolves the *types.Func and *ast.FuncDecl item refers to.
+func (h *LangHandler) resolveCallHierarchyItem(ctx context.Context, conn jsonrpc2.JSONRPC2, item lsp.CallHierarchyItem) (*token.FileSet, *loader.Program, *loader.PackageInfo, *types.Func, *ast.FuncDecl, error) {
+	fset, _, nodes, program, pkg, err := h.typecheck(ctx, conn, DocumentURI(item.URI), item.SelectionRange.Start)
+	if err != nil {
+		if _, ok := err.(*invalidNodeError); !ok {
+			return nil, nil, nil, nil, nil, err
+		}
+	}
+	fn, decl := enclosingFunc(program, pkg, nodes)
+	return fset, program, pkg, fn, decl, nil
+}
+
+// enclosingFunc resolves the *types.Func for the identifier (or selector, or
+// func declaration) found while walking outward from the cursor through
+// nodes, along with its *ast.FuncDecl when one exists. Interface methods
+// have no *ast.FuncDecl of their own - they're an *ast.Field inside an
+// *ast.InterfaceType, not a function declaration - so decl is nil for them;
+// callers must treat a non-nil fn with a nil decl as valid, and only skip
+// work that genuinely requires a body or declaration syntax.
+func enclosingFunc(program *loader.Program, pkg *loader.PackageInfo, nodes []ast.Node) (*types.Func, *ast.FuncDecl) {
+	id := identFromNodes(nodes)
+	if id == nil {
+		return nil, nil
+	}
+	fn, ok := pkg.ObjectOf(id).(*types.Func)
+	if !ok {
+		return nil, nil
+	}
+	return fn, funcDeclForObject(program, fn)
+}
+
+// identFromNodes returns the first identifier found while walking outward
+// from the cursor, handling plain idents, selector expressions (the
+// selected name), and func declarations (the func's own name).
+func identFromNodes(nodes []ast.Node) *ast.Ident {
+	for _, n := range nodes {
+		switch t := n.(type) {
+		case *ast.Ident:
+			return t
+		case *ast.SelectorExpr:
+			return t.Sel
+		case *ast.FuncDecl:
+			return t.Name
+		}
+	}
+	return nil
+}
+
+// funcDeclForObject finds the *ast.FuncDecl that declares fn anywhere in
+// the loaded program.
+func funcDeclForObject(program *loader.Program, fn *types.Func) *ast.FuncDecl {
+	if !fn.Pos().IsValid() {
+		return nil
+	}
+	_, path, _ := program.PathEnclosingInterval(fn.Pos(), fn.Pos())
+	for _, p := range path {
+		if decl, ok := p.(*ast.FuncDecl); ok {
+			return decl
+		}
+	}
+	return nil
+}
+
+// enclosingFuncDecl finds the *ast.FuncDecl enclosing an arbitrary node.
+func enclosingFuncDecl(program *loader.Program, node ast.Node) *ast.FuncDecl {
+	_, path, _ := program.PathEnclosingInterval(node.Pos(), node.Pos())
+	for _, p := range path {
+		if decl, ok := p.(*ast.FuncDecl); ok {
+			return decl
+		}
+	}
+	return nil
+}
+
+// calleeObject resolves the types.Object that a call expression's function
+// operand refers to.
+func calleeObject(program *loader.Program, pkg *loader.PackageInfo, call *ast.CallExpr) types.Object {
+	id := ident(program, pkg, call.Fun)
+	if id == nil {
+		return nil
+	}
+	return pkg.ObjectOf(id)
+}
+
+// callHierarchyTargets returns target plus, when target is an interface
+// method, the matching method of every concrete type in the program that
+// implements that interface - so a call made directly on any implementation
+// is reported as a caller of the interface method too. A concrete method
+// target is not expanded: calls dispatched through an interface resolve to
+// the interface method and are not matched against it.
+func callHierarchyTargets(program *loader.Program, target *types.Func) []types.Object {
+	targets := []types.Object{target}
+	sig, ok := target.Type().(*types.Signature)
+	if !ok || sig.Recv() == nil {
+		return targets
+	}
+	iface, ok := sig.Recv().Type().Underlying().(*types.Interface)
+	if !ok {
+		return targets
+	}
+	for _, info := range program.AllPackages {
+		scope := info.Pkg.Scope()
+		for _, name := range scope.Names() {
+			tn, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok {
+				continue
+			}
+			named, ok := tn.Type().(*types.Named)
+			if !ok {
+				continue
+			}
+			if !types.Implements(named, iface) && !types.Implements(types.NewPointer(named), iface) {
+				continue
+			}
+			if m := lookupMethod(named, target.Name()); m != nil {
+				targets = append(targets, m)
+			}
+		}
+	}
+	return targets
+}
+
+// lookupMethod returns named's (or its pointer's) method with the given
+// name, if it has one.
+func lookupMethod(named *types.Named, name string) *types.Func {
+	for i := 0; i < named.NumMethods(); i++ {
+		if m := named.Method(i); m.Name() == name {
+			return m
+		}
+	}
+	mset := types.NewMethodSet(types.NewPointer(named))
+	for i := 0; i < mset.Len(); i++ {
+		if fn, ok := mset.At(i).Obj().(*types.Func); ok && fn.Name() == name {
+			return fn
+		}
+	}
+	return nil
+}
+
+// containsObject reports whether o is present in objs.
+func containsObject(objs []types.Object, o types.Object) bool {
+	for _, x := range objs {
+		if x == o {
+			return true
+		}
+	}
+	return false
+}
+
+// callHierarchyItem builds the lsp.CallHierarchyItem for fn. decl is its
+// declaration when one exists; it is nil for an interface method, which has
+// no *ast.FuncDecl to point at, so we fall back to a zero-width range at
+// fn's own position (the method name inside the interface's field list).
+func callHierarchyItem(fset *token.FileSet, fn *types.Func, decl *ast.FuncDecl) lsp.CallHierarchyItem {
+	isMethod := false
+	if sig, ok := fn.Type().(*types.Signature); ok {
+		isMethod = sig.Recv() != nil
+	}
+	kind := lsp.SKFunction
+	if isMethod {
+		kind = lsp.SKMethod
+	}
+
+	if decl != nil {
+		return lsp.CallHierarchyItem{
+			Name:           fn.Name(),
+			Kind:           kind,
+			URI:            string(URIFromPath(fset.Position(decl.Pos()).Filename)),
+			Range:          rangeForNode(fset, decl),
+			SelectionRange: rangeForNode(fset, decl.Name),
+		}
+	}
+
+	p := fset.Position(fn.Pos())
+	point := lsp.Range{
+		Start: lsp.Position{Line: p.Line - 1, Character: p.Column - 1},
+		End:   lsp.Position{Line: p.Line - 1, Character: p.Column - 1},
+	}
+	return lsp.CallHierarchyItem{
+		Name:           fn.Name(),
+		Kind:           kind,
+		URI:            string(URIFromPath(p.Filename)),
+		Range:          point,
+		SelectionRange: point,
+	}
+}
+
+// rangeForNode converts node's source extent to an lsp.Range.
+func rangeForNode(fset *token.FileSet, node ast.Node) lsp.Range {
+	start := fset.Position(node.Pos())
+	end := fset.Position(node.End())
+	return lsp.Range{
+		Start: lsp.Position{Line: start.Line - 1, Character: start.Column - 1},
+		End:   lsp.Position{Line: end.Line - 1, Character: end.Column - 1},
+	}
+}
@@ -0,0 +1,211 @@
+package langserver
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"sort"
+	"testing"
+
+	"golang.org/x/tools/go/loader"
+)
+
+const callHierarchyTestSrc = `package p
+
+type Fooer interface {
+	Foo(x int) string
+}
+
+type A struct{}
+
+func (A) Foo(x int) string { return "a" }
+
+type B struct{}
+
+func (B) Foo(x int) string { return "b" }
+
+func UseFooer(f Fooer) string {
+	return f.Foo(1)
+}
+
+func Recurse(n int) int {
+	if n == 0 {
+		return 0
+	}
+	return Recurse(n - 1)
+}
+`
+
+// loadCallHierarchyTestProgram parses and type-checks callHierarchyTestSrc,
+// returning the program and its sole package.
+func loadCallHierarchyTestProgram(t *testing.T) (*loader.Program, *loader.PackageInfo, *ast.File) {
+	t.Helper()
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", callHierarchyTestSrc, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	var conf loader.Config
+	conf.Fset = fset
+	conf.CreateFromFiles("p", f)
+	prog, err := conf.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return prog, prog.Created[0], f
+}
+
+// findFuncDeclName returns the position of the Name identifier of the
+// *ast.FuncDecl matching name, optionally restricted to methods on recv.
+func findFuncDeclName(f *ast.File, name, recv string) token.Pos {
+	var pos token.Pos
+	ast.Inspect(f, func(n ast.Node) bool {
+		decl, ok := n.(*ast.FuncDecl)
+		if !ok || decl.Name.Name != name {
+			return true
+		}
+		if recv == "" {
+			if decl.Recv != nil {
+				return true
+			}
+		} else {
+			if decl.Recv == nil || len(decl.Recv.List) != 1 {
+				return true
+			}
+			id, ok := decl.Recv.List[0].Type.(*ast.Ident)
+			if !ok || id.Name != recv {
+				return true
+			}
+		}
+		pos = decl.Name.Pos()
+		return false
+	})
+	return pos
+}
+
+// findInterfaceMethodName returns the position of the method-name identifier
+// declared inside an interface type.
+func findInterfaceMethodName(f *ast.File, iface, method string) token.Pos {
+	var pos token.Pos
+	ast.Inspect(f, func(n ast.Node) bool {
+		spec, ok := n.(*ast.TypeSpec)
+		if !ok || spec.Name.Name != iface {
+			return true
+		}
+		it, ok := spec.Type.(*ast.InterfaceType)
+		if !ok {
+			return true
+		}
+		for _, field := range it.Methods.List {
+			for _, nameIdent := range field.Names {
+				if nameIdent.Name == method {
+					pos = nameIdent.Pos()
+				}
+			}
+		}
+		return false
+	})
+	return pos
+}
+
+func TestEnclosingFunc_ConcreteMethod(t *testing.T) {
+	prog, pkg, f := loadCallHierarchyTestProgram(t)
+	pos := findFuncDeclName(f, "Foo", "A")
+	if !pos.IsValid() {
+		t.Fatal("could not find A.Foo declaration")
+	}
+	_, nodes, _ := prog.PathEnclosingInterval(pos, pos)
+
+	fn, decl := enclosingFunc(prog, pkg, nodes)
+	if fn == nil {
+		t.Fatal("enclosingFunc returned nil *types.Func for a concrete method")
+	}
+	if decl == nil {
+		t.Fatal("enclosingFunc returned nil *ast.FuncDecl for a concrete method")
+	}
+	if fn.Name() != "Foo" {
+		t.Errorf("fn.Name() = %q, want %q", fn.Name(), "Foo")
+	}
+}
+
+func TestEnclosingFunc_InterfaceMethod(t *testing.T) {
+	prog, pkg, f := loadCallHierarchyTestProgram(t)
+	pos := findInterfaceMethodName(f, "Fooer", "Foo")
+	if !pos.IsValid() {
+		t.Fatal("could not find Fooer.Foo")
+	}
+	_, nodes, _ := prog.PathEnclosingInterval(pos, pos)
+
+	fn, decl := enclosingFunc(prog, pkg, nodes)
+	if fn == nil {
+		t.Fatal("enclosingFunc returned nil *types.Func for an interface method")
+	}
+	if decl != nil {
+		t.Errorf("enclosingFunc returned a non-nil *ast.FuncDecl for an interface method: %v", decl)
+	}
+}
+
+func TestCallHierarchyTargets_InterfaceDispatch(t *testing.T) {
+	prog, pkg, f := loadCallHierarchyTestProgram(t)
+	pos := findInterfaceMethodName(f, "Fooer", "Foo")
+	_, nodes, _ := prog.PathEnclosingInterval(pos, pos)
+	fn, _ := enclosingFunc(prog, pkg, nodes)
+	if fn == nil {
+		t.Fatal("enclosingFunc returned nil for Fooer.Foo")
+	}
+
+	targets := callHierarchyTargets(prog, fn)
+
+	var names []string
+	for _, obj := range targets {
+		f, ok := obj.(*types.Func)
+		if !ok {
+			continue
+		}
+		recv := ""
+		if sig, ok := f.Type().(*types.Signature); ok && sig.Recv() != nil {
+			recv = sig.Recv().Type().String()
+		}
+		names = append(names, recv)
+	}
+	sort.Strings(names)
+
+	// The interface method itself, plus A.Foo and B.Foo.
+	if len(names) != 3 {
+		t.Fatalf("callHierarchyTargets returned %d targets, want 3: %v", len(names), names)
+	}
+}
+
+func TestCallHierarchyTargets_Recursion(t *testing.T) {
+	prog, pkg, f := loadCallHierarchyTestProgram(t)
+	pos := findFuncDeclName(f, "Recurse", "")
+	if !pos.IsValid() {
+		t.Fatal("could not find Recurse declaration")
+	}
+	_, nodes, _ := prog.PathEnclosingInterval(pos, pos)
+	fn, decl := enclosingFunc(prog, pkg, nodes)
+	if fn == nil || decl == nil {
+		t.Fatal("enclosingFunc failed to resolve Recurse")
+	}
+
+	var calls int
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if callee := calleeObject(prog, pkg, call); callee == fn {
+			calls++
+		}
+		return true
+	})
+	if calls != 1 {
+		t.Fatalf("found %d self-calls in Recurse's body, want 1", calls)
+	}
+
+	targets := callHierarchyTargets(prog, fn)
+	if len(targets) != 1 || targets[0] != fn {
+		t.Fatalf("callHierarchyTargets(Recurse) = %v, want just itself (not a method, no interface expansion)", targets)
+	}
+}
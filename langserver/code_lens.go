@@ -0,0 +1,207 @@
+package langserver
+
+import (
+	"context"
+	"go/ast"
+	"go/token"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/sourcegraph/go-langserver/pkg/lsp"
+	"github.com/sourcegraph/jsonrpc2"
+	"golang.org/x/tools/go/loader"
+)
+
+var (
+	testFuncRe      = regexp.MustCompile(`^Test[A-Z]`)
+	benchmarkFuncRe = regexp.MustCompile(`^Benchmark[A-Z]`)
+	exampleFuncRe   = regexp.MustCompile(`^Example[A-Z]?`)
+	fuzzFuncRe      = regexp.MustCompile(`^Fuzz[A-Z]`)
+	goGenerateRe    = regexp.MustCompile(`^//go:generate\s+(.*)$`)
+)
+
+// handleTextDocumentCodeLens returns "go test"/"go generate"/"run" lenses
+// for the file at params.TextDocument.URI.
+func (h *LangHandler) handleTextDocumentCodeLens(ctx context.Context, conn jsonrpc2.JSONRPC2, req *jsonrpc2.Request, params lsp.CodeLensParams) ([]lsp.CodeLens, error) {
+	uri := DocumentURI(params.TextDocument.URI)
+	fset, _, _, _, pkg, err := h.typecheck(ctx, conn, uri, lsp.Position{})
+	if err != nil {
+		if _, ok := err.(*invalidNodeError); !ok {
+			return nil, err
+		}
+	}
+
+	path := uri.Filename()
+	file := fileForPath(fset, pkg, path)
+	if file == nil {
+		return nil, nil
+	}
+
+	pkgDir := filepath.Dir(path)
+	isTestFile := strings.HasSuffix(path, "_test.go")
+
+	var lenses []lsp.CodeLens
+	var sawTest bool
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil {
+			continue
+		}
+		switch {
+		case isTestFile && testFuncRe.MatchString(fn.Name.Name):
+			lenses = append(lenses, testLens(fset, fn, "test", pkgDir))
+			sawTest = true
+		case isTestFile && benchmarkFuncRe.MatchString(fn.Name.Name):
+			lenses = append(lenses, testLens(fset, fn, "benchmark", pkgDir))
+			sawTest = true
+		case isTestFile && exampleFuncRe.MatchString(fn.Name.Name):
+			lenses = append(lenses, testLens(fset, fn, "example", pkgDir))
+			sawTest = true
+		case isTestFile && fuzzFuncRe.MatchString(fn.Name.Name):
+			lenses = append(lenses, testLens(fset, fn, "fuzz", pkgDir))
+			sawTest = true
+		case fn.Name.Name == "main" && file.Name.Name == "main":
+			lenses = append(lenses, lsp.CodeLens{
+				Range: rangeForNode(fset, fn.Name),
+				Command: &lsp.Command{
+					Title:     "run",
+					Command:   "go.run",
+					Arguments: []interface{}{pkgDir},
+				},
+			})
+		}
+	}
+
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			if !goGenerateRe.MatchString(c.Text) {
+				continue
+			}
+			lenses = append(lenses, lsp.CodeLens{
+				Range: rangeForNode(fset, c),
+				Command: &lsp.Command{
+					Title:     "go generate",
+					Command:   "go.generate",
+					Arguments: []interface{}{pkgDir},
+				},
+			})
+		}
+	}
+
+	if sawTest {
+		lenses = append([]lsp.CodeLens{{
+			Range: lsp.Range{Start: lsp.Position{Line: 0}, End: lsp.Position{Line: 0}},
+			Command: &lsp.Command{
+				Title:     "run package tests",
+				Command:   "go.test",
+				Arguments: []interface{}{map[string]interface{}{"package": pkgDir}},
+			},
+		}}, lenses...)
+	}
+
+	return lenses, nil
+}
+
+// testLens builds the lens anchored on fn's name, carrying the information
+// the go.test execute-command handler needs to run just that one test.
+func testLens(fset *token.FileSet, fn *ast.FuncDecl, kind, pkgDir string) lsp.CodeLens {
+	return lsp.CodeLens{
+		Range: rangeForNode(fset, fn.Name),
+		Command: &lsp.Command{
+			Title:   "run " + kind,
+			Command: "go.test",
+			Arguments: []interface{}{map[string]interface{}{
+				"package": pkgDir,
+				"func":    fn.Name.Name,
+				"kind":    kind,
+			}},
+		},
+	}
+}
+
+// fileForPath returns the *ast.File belonging to pkg whose filename is path.
+func fileForPath(fset *token.FileSet, pkg *loader.PackageInfo, path string) *ast.File {
+	for _, f := range pkg.Files {
+		if fset.Position(f.Pos()).Filename == path {
+			return f
+		}
+	}
+	return nil
+}
+
+// handleWorkspaceExecuteCommand runs the go.test/go.generate/go.run
+// commands registered by handleTextDocumentCodeLens, shelling out to the
+// detected go binary and sending its output back as window/logMessage
+// notifications once the command has exited.
+func (h *LangHandler) handleWorkspaceExecuteCommand(ctx context.Context, conn jsonrpc2.JSONRPC2, req *jsonrpc2.Request, params lsp.ExecuteCommandParams) (interface{}, error) {
+	switch params.Command {
+	case "go.test":
+		return nil, h.runGoCommand(ctx, conn, params.Arguments, buildTestArgs)
+	case "go.generate":
+		return nil, h.runGoCommand(ctx, conn, params.Arguments, func(map[string]interface{}) []string {
+			return []string{"generate", "./..."}
+		})
+	case "go.run":
+		return nil, h.runGoCommand(ctx, conn, params.Arguments, func(map[string]interface{}) []string {
+			return []string{"run", "."}
+		})
+	}
+	return nil, nil
+}
+
+// buildTestArgs builds "go test" arguments, narrowing to a single test with
+// -run when arg carries a "func" name, or to a single benchmark with -bench
+// (and no tests) when its "kind" is "benchmark".
+func buildTestArgs(arg map[string]interface{}) []string {
+	args := []string{"test"}
+	if name, ok := arg["func"].(string); ok && name != "" {
+		if kind, _ := arg["kind"].(string); kind == "benchmark" {
+			args = append(args, "-run", "^$", "-bench", "^"+name+"$", "-v")
+		} else {
+			args = append(args, "-run", "^"+name+"$", "-v")
+		}
+	}
+	return append(args, ".")
+}
+
+// runGoCommand shells out to the go binary in the package directory carried
+// in rawArgs and waits for it to exit. Its combined output is then sent back
+// as a single window/logMessage notification, followed by an error
+// notification if the command failed; output is not streamed while the
+// command runs.
+func (h *LangHandler) runGoCommand(ctx context.Context, conn jsonrpc2.JSONRPC2, rawArgs []interface{}, argsFor func(map[string]interface{}) []string) error {
+	if len(rawArgs) == 0 {
+		return nil
+	}
+	var pkgDir string
+	var arg map[string]interface{}
+	switch v := rawArgs[0].(type) {
+	case string:
+		pkgDir = v
+	case map[string]interface{}:
+		arg = v
+		pkgDir, _ = v["package"].(string)
+	}
+	if pkgDir == "" {
+		return nil
+	}
+
+	cmd := exec.CommandContext(ctx, goBinary(), argsFor(arg)...)
+	cmd.Dir = pkgDir
+	out, err := cmd.CombinedOutput()
+	conn.Notify(ctx, "window/logMessage", &lsp.LogMessageParams{Type: lsp.MTInfo, Message: string(out)})
+	if err != nil {
+		conn.Notify(ctx, "window/logMessage", &lsp.LogMessageParams{Type: lsp.MTError, Message: err.Error()})
+	}
+	return nil
+}
+
+// goBinary returns the go binary to invoke commands with.
+func goBinary() string {
+	if path, err := exec.LookPath("go"); err == nil {
+		return path
+	}
+	return "go"
+}
@@ -0,0 +1,56 @@
+package langserver
+
+import "testing"
+
+func TestTestFuncRegexes(t *testing.T) {
+	tests := []struct {
+		name string
+		re   interface{ MatchString(string) bool }
+		in   string
+		want bool
+	}{
+		{"test match", testFuncRe, "TestFoo", true},
+		{"test lowercase suffix not matched", testFuncRe, "Testfoo", false},
+		{"test prefix only", testFuncRe, "Test", false},
+		{"benchmark match", benchmarkFuncRe, "BenchmarkFoo", true},
+		{"benchmark non-match", benchmarkFuncRe, "Benchfoo", false},
+		{"example match", exampleFuncRe, "ExampleFoo", true},
+		{"bare example matches", exampleFuncRe, "Example", true},
+		{"fuzz match", fuzzFuncRe, "FuzzFoo", true},
+		{"fuzz non-match", fuzzFuncRe, "Fuzzfoo", false},
+		{"go:generate directive", goGenerateRe, "//go:generate stringer -type=Kind", true},
+		{"plain comment not a directive", goGenerateRe, "// generate nothing here", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.re.MatchString(tt.in); got != tt.want {
+				t.Errorf("match(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildTestArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		arg  map[string]interface{}
+		want []string
+	}{
+		{"package run", map[string]interface{}{}, []string{"test", "."}},
+		{"single func", map[string]interface{}{"func": "TestFoo"}, []string{"test", "-run", "^TestFoo$", "-v", "."}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildTestArgs(tt.arg)
+			if len(got) != len(tt.want) {
+				t.Fatalf("buildTestArgs(%v) = %v, want %v", tt.arg, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("buildTestArgs(%v) = %v, want %v", tt.arg, got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package langserver
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"strings"
+
+	"github.com/sourcegraph/go-langserver/pkg/lsp"
+	"golang.org/x/tools/go/loader"
+)
+
+// postfixTemplate is one postfix-completion snippet, modeled on gopls'
+// completion/postfix_snippets.go. It fires when the selector after the dot
+// is a prefix of label, e.g. "x.if" offers the "if" template.
+type postfixTemplate struct {
+	label   string
+	detail  string
+	applies func(t types.Type) bool
+	snippet func(receiver string, returnsError bool) string
+}
+
+var postfixTemplates = []postfixTemplate{
+	{
+		label:   "if",
+		detail:  "if x { }",
+		applies: isBoolean,
+		snippet: func(recv string, _ bool) string {
+			return fmt.Sprintf("if %s {\n\t$0\n}", recv)
+		},
+	},
+	{
+		label:   "for",
+		detail:  "for i, v := range xs { }",
+		applies: isRangeable,
+		snippet: func(recv string, _ bool) string {
+			return fmt.Sprintf("for ${1:i}, ${2:v} := range %s {\n\t$0\n}", recv)
+		},
+	},
+	{
+		label:  "err",
+		detail: "if err != nil { return err }",
+		applies: func(t types.Type) bool {
+			return isError(t)
+		},
+		snippet: func(recv string, returnsError bool) string {
+			if !returnsError {
+				return ""
+			}
+			return fmt.Sprintf("if %s != nil {\n\treturn ${1:%s}\n}", recv, recv)
+		},
+	},
+	{
+		label:  "var",
+		detail: "name := x",
+		applies: func(types.Type) bool {
+			return true
+		},
+		snippet: func(recv string, _ bool) string {
+			return fmt.Sprintf("${1:name} := %s", recv)
+		},
+	},
+	{
+		label:   "append",
+		detail:  "xs = append(xs, v)",
+		applies: isSlice,
+		snippet: func(recv string, _ bool) string {
+			return fmt.Sprintf("%s = append(%s, ${1})", recv, recv)
+		},
+	},
+	{
+		label:   "sort",
+		detail:  "sort.Slice(xs, less)",
+		applies: isSlice,
+		snippet: func(recv string, _ bool) string {
+			return fmt.Sprintf("sort.Slice(%s, func(i, j int) bool { return ${1} })", recv)
+		},
+	},
+}
+
+// postfixSnippetCompletions returns the postfix-snippet completion
+// candidates, if any, for the selector expression at the cursor - e.g.
+// "xs.for" typed after a slice expression "xs".
+func postfixSnippetCompletions(fset *token.FileSet, program *loader.Program, pkg *loader.PackageInfo, nodes []ast.Node) []lsp.CompletionItem {
+	sel := selectorAtCursor(nodes)
+	if sel == nil {
+		return nil
+	}
+	recvType := pkg.TypeOf(sel.X)
+	if recvType == nil {
+		return nil
+	}
+	receiver := nodeAsString(sel.X, fset)
+	returnsError := funcReturnsError(program, sel)
+	typed, selRange := typedSelectorName(fset, sel)
+
+	var items []lsp.CompletionItem
+	for _, tmpl := range postfixTemplates {
+		if !strings.HasPrefix(tmpl.label, typed) {
+			continue
+		}
+		if tmpl.applies != nil && !tmpl.applies(recvType) {
+			continue
+		}
+		text := tmpl.snippet(receiver, returnsError)
+		if text == "" {
+			continue
+		}
+		items = append(items, lsp.CompletionItem{
+			Label:            "." + tmpl.label,
+			Detail:           tmpl.detail,
+			InsertTextFormat: lsp.ITFSnippet,
+			TextEdit: &lsp.TextEdit{
+				Range:   selRange,
+				NewText: text,
+			},
+		})
+	}
+	return items
+}
+
+// typedSelectorName returns the text the user actually typed after the dot
+// in sel, along with the lsp.Range it occupies. Usually that's just
+// sel.Sel.Name at sel.Sel's own position, but go/parser substitutes the
+// error-recovery placeholder name "_" - not the literal text - whenever the
+// selected name parses as a keyword, which is exactly the case for two of
+// our triggers ("if" and "for"). When that happens, the placeholder's
+// position still marks where the typed text starts, so we recover it by
+// reading the raw source bytes forward from there to the next non-identifier
+// byte.
+func typedSelectorName(fset *token.FileSet, sel *ast.SelectorExpr) (string, lsp.Range) {
+	if sel.Sel.Name != "_" {
+		return sel.Sel.Name, rangeForNode(fset, sel)
+	}
+
+	start := fset.Position(sel.Sel.Pos())
+	src, err := os.ReadFile(start.Filename)
+	if err != nil {
+		return sel.Sel.Name, rangeForNode(fset, sel)
+	}
+
+	offset := start.Offset
+	end := offset
+	for end < len(src) && isIdentByte(src[end]) {
+		end++
+	}
+	if end == offset {
+		return sel.Sel.Name, rangeForNode(fset, sel)
+	}
+
+	file := fset.File(sel.Sel.Pos())
+	selStart := fset.Position(sel.Pos())
+	selEnd := fset.Position(file.Pos(end))
+	return string(src[offset:end]), lsp.Range{
+		Start: lsp.Position{Line: selStart.Line - 1, Character: selStart.Column - 1},
+		End:   lsp.Position{Line: selEnd.Line - 1, Character: selEnd.Column - 1},
+	}
+}
+
+// isIdentByte reports whether b can appear in a Go identifier. Postfix
+// triggers are always ASCII, so this need not handle non-ASCII letters.
+func isIdentByte(b byte) bool {
+	return b == '_' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
+
+// selectorAtCursor returns the *ast.SelectorExpr enclosing the cursor.
+func selectorAtCursor(nodes []ast.Node) *ast.SelectorExpr {
+	for _, n := range nodes {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			return sel
+		}
+	}
+	return nil
+}
+
+// funcReturnsError reports whether the function declaration enclosing node
+// has a final result of type error, gating the ".err" template.
+func funcReturnsError(program *loader.Program, node ast.Node) bool {
+	decl := enclosingFuncDecl(program, node)
+	if decl == nil || decl.Type.Results == nil {
+		return false
+	}
+	results := decl.Type.Results.List
+	if len(results) == 0 {
+		return false
+	}
+	id, ok := results[len(results)-1].Type.(*ast.Ident)
+	return ok && id.Name == "error"
+}
+
+func isBoolean(t types.Type) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsBoolean != 0
+}
+
+func isSlice(t types.Type) bool {
+	_, ok := t.Underlying().(*types.Slice)
+	return ok
+}
+
+func isRangeable(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Slice, *types.Array, *types.Map, *types.Chan:
+		return true
+	}
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsString != 0
+}
+
+// isError reports whether t satisfies the built-in error interface - not
+// just whether it literally is that interface, so that a concrete wrapped
+// error type (e.g. the result of a custom *MyErr implementing Error()
+// string) is recognized too.
+func isError(t types.Type) bool {
+	errType, ok := types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+	if !ok {
+		return false
+	}
+	return types.Implements(t, errType)
+}
@@ -0,0 +1,176 @@
+package langserver
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/loader"
+)
+
+// findSelector returns the first *ast.SelectorExpr found in f.
+func findSelector(f *ast.File) *ast.SelectorExpr {
+	var sel *ast.SelectorExpr
+	ast.Inspect(f, func(n ast.Node) bool {
+		if sel != nil {
+			return false
+		}
+		if s, ok := n.(*ast.SelectorExpr); ok {
+			sel = s
+			return false
+		}
+		return true
+	})
+	return sel
+}
+
+func TestTypedSelectorName(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"keyword if", "package p\n\nfunc Use(xs []int) {\n\txs.if\n}\n", "if"},
+		{"keyword for", "package p\n\nfunc Use(xs []int) {\n\txs.for\n}\n", "for"},
+		{"plain identifier", "package p\n\nfunc Use(xs []int) {\n\txs.sor\n}\n", "sor"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "p.go")
+			if err := os.WriteFile(path, []byte(tt.src), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			fset := token.NewFileSet()
+			f, _ := parser.ParseFile(fset, path, tt.src, 0)
+			if f == nil {
+				t.Fatal("parser.ParseFile returned no AST")
+			}
+			sel := findSelector(f)
+			if sel == nil {
+				t.Fatal("no selector expression found in source")
+			}
+			got, _ := typedSelectorName(fset, sel)
+			if got != tt.want {
+				t.Errorf("typedSelectorName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+const postfixTestSrc = `package p
+
+func Use(xs []int, b bool) error {
+	_ = xs
+	_ = b
+	return nil
+}
+`
+
+// loadPostfixTestProgram type-checks postfixTestSrc so its identifiers have
+// resolved types, then re-parses src (valid Go with a dangling selector
+// expression standing in for what the user is mid-typing) into its own
+// *ast.File sharing the same FileSet, returning PathEnclosingInterval nodes
+// rooted at the selector.
+func loadPostfixTestProgram(t *testing.T, selectorSrc string) (*token.FileSet, *loader.Program, *loader.PackageInfo, []ast.Node) {
+	t.Helper()
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", postfixTestSrc+"\n"+selectorSrc, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	var conf loader.Config
+	conf.Fset = fset
+	conf.AllowErrors = true
+	conf.TypeChecker.Error = func(error) {}
+	conf.CreateFromFiles("p", f)
+	prog, err := conf.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	pkg := prog.Created[0]
+
+	sel := findLastSelector(f)
+	if sel == nil {
+		t.Fatal("no selector expression found in source")
+	}
+	_, nodes, _ := prog.PathEnclosingInterval(sel.Pos(), sel.Pos())
+	return fset, prog, pkg, nodes
+}
+
+func findLastSelector(f *ast.File) *ast.SelectorExpr {
+	var sel *ast.SelectorExpr
+	ast.Inspect(f, func(n ast.Node) bool {
+		if s, ok := n.(*ast.SelectorExpr); ok {
+			sel = s
+		}
+		return true
+	})
+	return sel
+}
+
+func TestPostfixSnippetCompletions_TypeGating(t *testing.T) {
+	tests := []struct {
+		name       string
+		selectorGo string
+		wantLabels []string
+	}{
+		{
+			name:       "slice prefix matching append",
+			selectorGo: "func g1() { var xs []int; xs.ap }",
+			wantLabels: []string{".append"},
+		},
+		{
+			name:       "slice prefix matching sort",
+			selectorGo: "func g2() { var xs []int; xs.so }",
+			wantLabels: []string{".sort"},
+		},
+		{
+			name:       "bool does not offer append",
+			selectorGo: "func g3() { var b bool; b.ap }",
+			wantLabels: nil,
+		},
+		{
+			name:       "var template applies to any type",
+			selectorGo: "func g4() { var b bool; b.va }",
+			wantLabels: []string{".var"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset, prog, pkg, nodes := loadPostfixTestProgram(t, tt.selectorGo)
+			items := postfixSnippetCompletions(fset, prog, pkg, nodes)
+
+			var labels []string
+			for _, item := range items {
+				labels = append(labels, item.Label)
+			}
+			if len(labels) != len(tt.wantLabels) {
+				t.Fatalf("postfixSnippetCompletions labels = %v, want %v", labels, tt.wantLabels)
+			}
+			for i := range labels {
+				if labels[i] != tt.wantLabels[i] {
+					t.Errorf("postfixSnippetCompletions labels = %v, want %v", labels, tt.wantLabels)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestPostfixSnippetCompletions_ErrTemplateRequiresErrorResult(t *testing.T) {
+	fset, prog, pkg, nodes := loadPostfixTestProgram(t, "func g5() error { var e error; e.er; return nil }")
+	items := postfixSnippetCompletions(fset, prog, pkg, nodes)
+	var found bool
+	for _, item := range items {
+		if item.Label == ".err" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected .err template when enclosing function returns error")
+	}
+}
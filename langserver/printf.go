@@ -0,0 +1,352 @@
+package langserver
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/go-langserver/pkg/lsp"
+	"golang.org/x/tools/go/loader"
+)
+
+// printfFunc identifies a well-known printf-like function by package and
+// name, inspired by the vet printf checker's table.
+type printfFunc struct {
+	pkg        string
+	name       string
+	wrapsError bool // true only for the *Errorf family, where %w is valid
+	scans      bool // true only for the Sscanf family, whose args are pointers
+}
+
+var knownPrintfFuncs = []printfFunc{
+	{"fmt", "Printf", false, false},
+	{"fmt", "Sprintf", false, false},
+	{"fmt", "Fprintf", false, false},
+	{"fmt", "Errorf", true, false},
+	{"fmt", "Sscanf", false, true},
+	{"log", "Printf", false, false},
+	{"log", "Fatalf", false, false},
+	{"log", "Panicf", false, false},
+}
+
+// printfCall describes a resolved printf-like call: its format argument and
+// the variadic arguments that follow it.
+type printfCall struct {
+	format     ast.Expr
+	args       []ast.Expr
+	wrapsError bool
+	scans      bool // args are pointers to be filled in, as with Sscanf
+}
+
+// classifyPrintfCall reports whether call invokes a printf-like function -
+// one of knownPrintfFuncs, or any function whose doc comment contains
+// "printf-like", or any function whose name ends in "f" and whose signature
+// ends in (format string, args ...interface{}) - and if so returns its
+// format argument and the arguments meant to fill it.
+func classifyPrintfCall(program *loader.Program, pkg *loader.PackageInfo, call *ast.CallExpr) *printfCall {
+	fn, ok := calleeObject(program, pkg, call).(*types.Func)
+	if !ok {
+		return nil
+	}
+	wrapsError := false
+	scans := false
+	known := false
+	if fn.Pkg() != nil {
+		for _, k := range knownPrintfFuncs {
+			if fn.Pkg().Name() == k.pkg && fn.Name() == k.name {
+				known = true
+				wrapsError = k.wrapsError
+				scans = k.scans
+				break
+			}
+		}
+	}
+	if !known {
+		decl := funcDeclForObject(program, fn)
+		if decl == nil || !looksPrintfLike(decl, fn) {
+			return nil
+		}
+	}
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || !sig.Variadic() {
+		return nil
+	}
+	formatIndex := sig.Params().Len() - 2
+	if formatIndex < 0 || formatIndex >= len(call.Args) {
+		return nil
+	}
+	return &printfCall{
+		format:     call.Args[formatIndex],
+		args:       call.Args[formatIndex+1:],
+		wrapsError: wrapsError,
+		scans:      scans,
+	}
+}
+
+// looksPrintfLike implements the heuristic for user-defined printf-style
+// functions not in knownPrintfFuncs.
+func looksPrintfLike(decl *ast.FuncDecl, fn *types.Func) bool {
+	if decl.Doc != nil && strings.Contains(decl.Doc.Text(), "printf-like") {
+		return true
+	}
+	if !strings.HasSuffix(fn.Name(), "f") {
+		return false
+	}
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || !sig.Variadic() || sig.Params().Len() < 2 {
+		return false
+	}
+	params := sig.Params()
+	format := params.At(params.Len() - 2)
+	variadic := params.At(params.Len() - 1)
+	if !isStringType(format.Type()) {
+		return false
+	}
+	slice, ok := variadic.Type().(*types.Slice)
+	return ok && isEmptyInterface(slice.Elem())
+}
+
+// printfVerb is one parsed conversion from a format string, e.g. the
+// "%[2]*.2f" in "%[2]*.2f apples".
+type printfVerb struct {
+	argIndex int
+	verb     byte
+}
+
+// parsePrintfVerbs tokenizes format into its conversions, tracking just
+// enough state to line each verb up with an argument position: explicit
+// %[n] indices, one consumed argument per '*' width/precision, and the
+// final verb letter. Returns nil if format is not a well-formed format
+// string (e.g. a trailing unterminated "%"), and an empty, non-nil slice if
+// it is well-formed but has no conversions.
+func parsePrintfVerbs(format string) []printfVerb {
+	verbs := []printfVerb{}
+	argIndex := 0
+	i := 0
+	for i < len(format) {
+		if format[i] != '%' {
+			i++
+			continue
+		}
+		i++
+		if i >= len(format) {
+			return nil
+		}
+		if format[i] == '%' {
+			i++
+			continue
+		}
+		if format[i] == '[' {
+			end := strings.IndexByte(format[i:], ']')
+			if end < 0 {
+				return nil
+			}
+			if n, err := strconv.Atoi(format[i+1 : i+end]); err == nil {
+				argIndex = n - 1
+			}
+			i += end + 1
+		}
+		for i < len(format) && strings.ContainsRune("+-# 0", rune(format[i])) {
+			i++
+		}
+		i, argIndex = skipStarOrDigits(format, i, &argIndex)
+		if i < len(format) && format[i] == '.' {
+			i++
+			i, argIndex = skipStarOrDigits(format, i, &argIndex)
+		}
+		if i >= len(format) {
+			return nil
+		}
+		verb := format[i]
+		i++
+		if verb == '%' {
+			continue
+		}
+		verbs = append(verbs, printfVerb{argIndex: argIndex, verb: verb})
+		argIndex++
+	}
+	return verbs
+}
+
+// skipStarOrDigits consumes a width or precision specifier starting at i: a
+// literal "*" consumes one argument, digits consume none.
+func skipStarOrDigits(format string, i int, argIndex *int) (int, int) {
+	if i < len(format) && format[i] == '*' {
+		*argIndex++
+		return i + 1, *argIndex
+	}
+	for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+		i++
+	}
+	return i, *argIndex
+}
+
+// verbAcceptsType reports whether t is a plausible argument for verb. scans
+// is true for the Sscanf family, whose arguments are pointers to be filled
+// in rather than values to be formatted, so t is dereferenced once before
+// applying the usual checks.
+func verbAcceptsType(verb byte, wrapsError, scans bool, t types.Type) bool {
+	if scans {
+		if ptr, ok := t.Underlying().(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+	}
+	switch verb {
+	case 'v', 'T', 'p':
+		return true
+	case 'w':
+		return wrapsError && isError(t)
+	case 'x', 'X':
+		// %x/%X also hex-encode strings and byte slices, not just numbers.
+		return isNumeric(t) || isStringType(t) || isByteSlice(t)
+	case 'd', 'b', 'o', 'c', 'U':
+		return isNumeric(t)
+	case 'e', 'E', 'f', 'F', 'g', 'G':
+		return isNumeric(t)
+	case 's', 'q':
+		return isStringType(t) || isStringer(t) || isByteSlice(t)
+	case 't':
+		return isBoolean(t)
+	}
+	return true
+}
+
+func isNumeric(t types.Type) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Info()&(types.IsInteger|types.IsFloat|types.IsComplex) != 0
+}
+
+func isStringType(t types.Type) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsString != 0
+}
+
+func isEmptyInterface(t types.Type) bool {
+	iface, ok := t.Underlying().(*types.Interface)
+	return ok && iface.NumMethods() == 0
+}
+
+// isStringer reports whether t has a "String() string" method.
+func isStringer(t types.Type) bool {
+	mset := types.NewMethodSet(t)
+	for i := 0; i < mset.Len(); i++ {
+		fn, ok := mset.At(i).Obj().(*types.Func)
+		if !ok || fn.Name() != "String" {
+			continue
+		}
+		sig, ok := fn.Type().(*types.Signature)
+		if ok && sig.Params().Len() == 0 && sig.Results().Len() == 1 && isStringType(sig.Results().At(0).Type()) {
+			return true
+		}
+	}
+	return false
+}
+
+func isByteSlice(t types.Type) bool {
+	slice, ok := t.Underlying().(*types.Slice)
+	if !ok {
+		return false
+	}
+	basic, ok := slice.Elem().Underlying().(*types.Basic)
+	return ok && basic.Kind() == types.Byte
+}
+
+// printfDiagnostics validates every printf-like call in file and returns
+// one diagnostic per unterminated format string or verb/argument mismatch.
+func printfDiagnostics(fset *token.FileSet, program *loader.Program, pkg *loader.PackageInfo, file *ast.File) []lsp.Diagnostic {
+	var diags []lsp.Diagnostic
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		info := classifyPrintfCall(program, pkg, call)
+		if info == nil {
+			return true
+		}
+		lit, ok := info.format.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		format, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return true
+		}
+		verbs := parsePrintfVerbs(format)
+		if verbs == nil {
+			diags = append(diags, lsp.Diagnostic{
+				Range:    rangeForNode(fset, lit),
+				Severity: lsp.Error,
+				Source:   "printf",
+				Message:  "unterminated format specifier",
+			})
+			return true
+		}
+		for _, v := range verbs {
+			if v.argIndex < 0 || v.argIndex >= len(info.args) {
+				diags = append(diags, lsp.Diagnostic{
+					Range:    rangeForNode(fset, call),
+					Severity: lsp.Error,
+					Source:   "printf",
+					Message:  fmt.Sprintf("%%%c needs argument %d, have %d", v.verb, v.argIndex+1, len(info.args)),
+				})
+				continue
+			}
+			argType := pkg.TypeOf(info.args[v.argIndex])
+			if argType == nil || verbAcceptsType(v.verb, info.wrapsError, info.scans, argType) {
+				continue
+			}
+			diags = append(diags, lsp.Diagnostic{
+				Range:    rangeForNode(fset, info.args[v.argIndex]),
+				Severity: lsp.Warning,
+				Source:   "printf",
+				Message:  fmt.Sprintf("%%%c has arg of type %s, which doesn't satisfy the verb", v.verb, argType.String()),
+			})
+		}
+		return true
+	})
+	return diags
+}
+
+// printfVerbCompletions are the verbs offered inside an open format literal,
+// in the order gofmt/vet conventionally suggest them.
+var printfVerbCompletions = []struct{ verb, detail string }{
+	{"%s", "string"},
+	{"%d", "integer"},
+	{"%v", "default format"},
+	{"%+v", "default format, with field names"},
+	{"%q", "quoted string"},
+	{"%w", "wrapped error (Errorf only)"},
+}
+
+// printfVerbCompletionItems returns the verb completions for a printf-like
+// call, each annotated with the type of the argument at argPos when the call
+// already has one. It does not check the cursor position or whether the
+// format literal is terminated.
+func printfVerbCompletionItems(program *loader.Program, pkg *loader.PackageInfo, call *ast.CallExpr, argPos int) []lsp.CompletionItem {
+	info := classifyPrintfCall(program, pkg, call)
+	if info == nil {
+		return nil
+	}
+	var items []lsp.CompletionItem
+	for _, c := range printfVerbCompletions {
+		if c.verb == "%w" && !info.wrapsError {
+			continue
+		}
+		detail := c.detail
+		if argPos < len(info.args) {
+			if t := pkg.TypeOf(info.args[argPos]); t != nil {
+				detail = fmt.Sprintf("%s (next arg is %s)", c.detail, t.String())
+			}
+		}
+		items = append(items, lsp.CompletionItem{
+			Label:  c.verb,
+			Detail: detail,
+			Kind:   lsp.CIKText,
+		})
+	}
+	return items
+}
@@ -0,0 +1,95 @@
+package langserver
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/go/loader"
+)
+
+func TestParsePrintfVerbs(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		want   []printfVerb
+	}{
+		{"simple", "%s and %d", []printfVerb{{0, 's'}, {1, 'd'}}},
+		{"literal percent", "100%%", []printfVerb{}},
+		{"width star consumes an arg", "%*d", []printfVerb{{1, 'd'}}},
+		{"precision star consumes an arg", "%.*f", []printfVerb{{1, 'f'}}},
+		{"explicit index", "%[2]d %d", []printfVerb{{1, 'd'}, {2, 'd'}}},
+		{"unterminated", "value: %", nil},
+		{"flags and width", "%+05.2f", []printfVerb{{0, 'f'}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parsePrintfVerbs(tt.format)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePrintfVerbs(%q) = %v, want %v", tt.format, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePrintfVerbs_PlainUnterminatedIsNil(t *testing.T) {
+	if got := parsePrintfVerbs("%"); got != nil {
+		t.Errorf("parsePrintfVerbs(%%) = %v, want nil", got)
+	}
+}
+
+const printfDiagnosticsTestSrc = `package p
+
+import "fmt"
+
+type MyErr struct{}
+
+func (*MyErr) Error() string { return "boom" }
+
+func ScanExample(s string) {
+	var n int
+	var name string
+	fmt.Sscanf(s, "%d %s", &n, &name)
+}
+
+func HexExample(b []byte, s string) {
+	fmt.Sprintf("%x", b)
+	fmt.Sprintf("%x", s)
+}
+
+func WrapExample() error {
+	e := &MyErr{}
+	return fmt.Errorf("wrap: %w", e)
+}
+`
+
+func loadPrintfTestProgram(t *testing.T) (*token.FileSet, *loader.Program, *loader.PackageInfo, *ast.File) {
+	t.Helper()
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", printfDiagnosticsTestSrc, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	var conf loader.Config
+	conf.Fset = fset
+	conf.CreateFromFiles("p", f)
+	prog, err := conf.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return fset, prog, prog.Created[0], f
+}
+
+// TestPrintfDiagnostics_NoFalsePositives covers three idiomatic patterns
+// that verbAcceptsType/isError must not flag: Sscanf's pointer arguments,
+// %x hex-encoding a string or byte slice, and %w wrapping a concrete error
+// type rather than the literal built-in error interface.
+func TestPrintfDiagnostics_NoFalsePositives(t *testing.T) {
+	fset, prog, pkg, f := loadPrintfTestProgram(t)
+	diags := printfDiagnostics(fset, prog, pkg, f)
+	if len(diags) != 0 {
+		t.Fatalf("printfDiagnostics reported %d false positives: %v", len(diags), diags)
+	}
+}
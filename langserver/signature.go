@@ -15,7 +15,7 @@ import (
 )
 
 func (h *LangHandler) handleTextDocumentSignatureHelp(ctx context.Context, conn jsonrpc2.JSONRPC2, req *jsonrpc2.Request, params lsp.TextDocumentPositionParams) (*lsp.SignatureHelp, error) {
-	fset, _, nodes, program, pkg, err := h.typecheck(ctx, conn, params.TextDocument.URI, params.Position)
+	fset, _, nodes, program, pkg, err := h.typecheck(ctx, conn, DocumentURI(params.TextDocument.URI), params.Position)
 	if err != nil {
 		if _, ok := err.(*invalidNodeError); !ok {
 			return nil, err
@@ -27,26 +27,190 @@ func (h *LangHandler) handleTextDocumentSignatureHelp(ctx context.Context, conn
 		return nil, nil
 	}
 
-	signature, parameters, doc := funcInfo(program, pkg, call.Fun)
-	if signature == "" {
+	signatures, activeSignature := signaturesForCall(program, pkg, call)
+	if len(signatures) == 0 {
 		return nil, nil
 	}
 
-	info := lsp.SignatureInformation{Label: signature, Documentation: doc}
-	info.Parameters = make([]lsp.ParameterInformation, len(parameters))
-	for i := 0; i < len(parameters); i++ {
-		info.Parameters[i] = lsp.ParameterInformation{Label: parameters[i]}
+	cursor := call.Lparen + 1
+	if len(nodes) > 0 {
+		cursor = nodes[0].Pos()
 	}
-	activeParameter := len(info.Parameters)
-	if activeParameter > 0 {
-		activeParameter = activeParameter - 1
+	activeParameter := activeParameterForCall(call, cursor)
+	if variadic, ok := variadicParamIndex(program, pkg, call); ok && activeParameter > variadic {
+		activeParameter = variadic
+	} else if numParams := len(signatures[activeSignature].Parameters); activeParameter >= numParams {
+		activeParameter = numParams - 1
 	}
-	numArguments := len(call.Args)
-	if activeParameter > numArguments {
-		activeParameter = numArguments
+	if activeParameter < 0 {
+		activeParameter = 0
 	}
 
-	return &lsp.SignatureHelp{Signatures: []lsp.SignatureInformation{info}, ActiveSignature: 0, ActiveParameter: activeParameter}, nil
+	return &lsp.SignatureHelp{Signatures: signatures, ActiveSignature: activeSignature, ActiveParameter: activeParameter}, nil
+}
+
+// signaturesForCall returns one SignatureInformation per candidate function:
+// normally just the one call.Fun resolves to, but when it resolves to an
+// interface method, one per concrete implementation found anywhere in the
+// program as well, and one hand-authored signature for calls to the
+// built-ins make, new, and append, which funcDecl can never resolve (they
+// have no *types.Func, let alone an *ast.FuncDecl). The callee is resolved
+// via calleeObject - not funcDecl - specifically so this also works for
+// interface methods, which never have a backing *ast.FuncDecl (they're an
+// *ast.Field inside an *ast.InterfaceType); signatureInfoForFunc covers that
+// case by formatting straight from the *types.Signature. ActiveSignature
+// selects whichever candidate's arity best matches the number of arguments
+// already supplied.
+func signaturesForCall(program *loader.Program, pkg *loader.PackageInfo, call *ast.CallExpr) ([]lsp.SignatureInformation, int) {
+	fn, ok := calleeObject(program, pkg, call).(*types.Func)
+	if !ok {
+		if sig, ok := builtinSignature(call); ok {
+			return []lsp.SignatureInformation{sig}, 0
+		}
+		return nil, 0
+	}
+
+	var signatures []lsp.SignatureInformation
+	best, bestDelta := 0, -1
+	for _, obj := range callHierarchyTargets(program, fn) {
+		candidate, ok := obj.(*types.Func)
+		if !ok {
+			continue
+		}
+		var info lsp.SignatureInformation
+		if decl := funcDeclForObject(program, candidate); decl != nil {
+			info = signatureInfoForDecl(program, pkg, decl)
+		} else {
+			info = signatureInfoForFunc(candidate)
+		}
+		delta := len(info.Parameters) - len(call.Args)
+		if delta < 0 {
+			delta = -delta
+		}
+		if bestDelta == -1 || delta < bestDelta {
+			best, bestDelta = len(signatures), delta
+		}
+		signatures = append(signatures, info)
+	}
+	if len(signatures) == 0 {
+		return nil, 0
+	}
+	return signatures, best
+}
+
+// signatureInfoForFunc builds an lsp.SignatureInformation straight from
+// fn's *types.Signature, labeling parameters by type only since names
+// aren't recoverable without a declaration's AST. Used for interface
+// methods, which have no *ast.FuncDecl of their own.
+func signatureInfoForFunc(fn *types.Func) lsp.SignatureInformation {
+	sig, _ := fn.Type().(*types.Signature)
+
+	var params []string
+	if sig != nil {
+		for i := 0; i < sig.Params().Len(); i++ {
+			t := sig.Params().At(i).Type()
+			if sig.Variadic() && i == sig.Params().Len()-1 {
+				if slice, ok := t.(*types.Slice); ok {
+					params = append(params, "..."+slice.Elem().String())
+					continue
+				}
+			}
+			params = append(params, t.String())
+		}
+	}
+
+	var results []string
+	if sig != nil {
+		for i := 0; i < sig.Results().Len(); i++ {
+			results = append(results, sig.Results().At(i).Type().String())
+		}
+	}
+
+	label := fn.Name() + "(" + strings.Join(params, ", ") + ")"
+	if len(results) == 1 {
+		label += " " + results[0]
+	} else if len(results) > 1 {
+		label += " (" + strings.Join(results, ", ") + ")"
+	}
+
+	info := lsp.SignatureInformation{Label: label}
+	info.Parameters = make([]lsp.ParameterInformation, len(params))
+	for i, p := range params {
+		info.Parameters[i] = lsp.ParameterInformation{Label: p}
+	}
+	return info
+}
+
+// activeParameterForCall returns the zero-based parameter index the cursor
+// sits in. call.Args holds one *ast.Expr per top-level argument, so commas
+// nested inside calls, composite literals, and string/rune literals never
+// get mistaken for argument separators.
+func activeParameterForCall(call *ast.CallExpr, cursor token.Pos) int {
+	for i, arg := range call.Args {
+		if cursor <= arg.End() {
+			return i
+		}
+	}
+	return len(call.Args)
+}
+
+// variadicParamIndex returns the index of call's variadic parameter, if it
+// has one, so ActiveParameter can be clamped to it instead of running past
+// the end of the parameter list when more arguments are supplied than
+// non-variadic parameters exist. This works directly off the callee's
+// *types.Signature rather than its *ast.FuncDecl, so it applies to
+// interface methods too, which have no declaration of their own.
+func variadicParamIndex(program *loader.Program, pkg *loader.PackageInfo, call *ast.CallExpr) (int, bool) {
+	fn, ok := calleeObject(program, pkg, call).(*types.Func)
+	if !ok {
+		return 0, false
+	}
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || !sig.Variadic() {
+		return 0, false
+	}
+	return sig.Params().Len() - 1, true
+}
+
+// builtinSignature returns a hand-authored signature for calls to make,
+// new, and append, none of which have an *ast.FuncDecl for funcDecl to find.
+func builtinSignature(call *ast.CallExpr) (lsp.SignatureInformation, bool) {
+	id, ok := call.Fun.(*ast.Ident)
+	if !ok || id.Obj != nil {
+		return lsp.SignatureInformation{}, false
+	}
+	b, ok := builtinSignatures[id.Name]
+	if !ok {
+		return lsp.SignatureInformation{}, false
+	}
+	info := lsp.SignatureInformation{Label: b.signature, Documentation: b.doc}
+	info.Parameters = make([]lsp.ParameterInformation, len(b.params))
+	for i, p := range b.params {
+		info.Parameters[i] = lsp.ParameterInformation{Label: p}
+	}
+	return info, true
+}
+
+var builtinSignatures = map[string]struct {
+	signature string
+	params    []string
+	doc       string
+}{
+	"make": {
+		signature: "make(t Type, size ...IntegerType) Type",
+		params:    []string{"t Type", "size ...IntegerType"},
+		doc:       "The built-in function make allocates and initializes an object of type slice, map, or chan (only).",
+	},
+	"new": {
+		signature: "new(Type) *Type",
+		params:    []string{"Type"},
+		doc:       "The built-in function new takes a type Type, allocates storage for a new item of that type, and returns a value of type *Type.",
+	},
+	"append": {
+		signature: "append(slice []Type, elems ...Type) []Type",
+		params:    []string{"slice []Type", "elems ...Type"},
+		doc:       "The built-in function append appends elements to the end of a slice.",
+	},
 }
 
 // callExpr climbs AST tree up until call expression
@@ -79,26 +243,45 @@ func funcInfo(prog *loader.Program, pkg *loader.PackageInfo, node ast.Node) (sig
 		if fDecl == nil {
 			return "", nil, ""
 		}
-		var doc string
-		if fDecl.Doc != nil {
-			doc = fDecl.Doc.Text()
-		}
-		parameters := parametersAsString(fDecl.Type.Params, pkg)
-		// do not print function body, docs, or parameters:
-		// we don't need body or docs and want parameters to be in form "name type"
-		// to enable highlighting of Nth parameter in IDE
-		// without custom parameters formatter we may have troubles dealing with
-		// "foo,bar baz" form of parameters declaration
-		clone := ast.FuncDecl{Recv: fDecl.Recv,
-			Name: fDecl.Name,
-			Type: &ast.FuncType{Params: &ast.FieldList{},
-				Results: fDecl.Type.Results}}
-		signature := nodeAsString(&clone, prog.Fset)
-		return strings.Replace(signature, "()", "("+strings.Join(parameters, ", ")+")", 1), parameters, doc
+		return declSignature(prog, pkg, fDecl)
 	}
 	return "", nil, ""
 }
 
+// declSignature formats fDecl's signature, its parameter labels ("name
+// type"), and its doc comment. Shared by funcInfo and by signature help's
+// multi-candidate rendering.
+func declSignature(prog *loader.Program, pkg *loader.PackageInfo, fDecl *ast.FuncDecl) (signature string, parameters []string, documentation string) {
+	var doc string
+	if fDecl.Doc != nil {
+		doc = fDecl.Doc.Text()
+	}
+	parameters = parametersAsString(fDecl.Type.Params, pkg)
+	// do not print function body, docs, or parameters:
+	// we don't need body or docs and want parameters to be in form "name type"
+	// to enable highlighting of Nth parameter in IDE
+	// without custom parameters formatter we may have troubles dealing with
+	// "foo,bar baz" form of parameters declaration
+	clone := ast.FuncDecl{Recv: fDecl.Recv,
+		Name: fDecl.Name,
+		Type: &ast.FuncType{Params: &ast.FieldList{},
+			Results: fDecl.Type.Results}}
+	sig := nodeAsString(&clone, prog.Fset)
+	return strings.Replace(sig, "()", "("+strings.Join(parameters, ", ")+")", 1), parameters, doc
+}
+
+// signatureInfoForDecl builds an lsp.SignatureInformation from a function
+// declaration.
+func signatureInfoForDecl(prog *loader.Program, pkg *loader.PackageInfo, fDecl *ast.FuncDecl) lsp.SignatureInformation {
+	signature, parameters, doc := declSignature(prog, pkg, fDecl)
+	info := lsp.SignatureInformation{Label: signature, Documentation: doc}
+	info.Parameters = make([]lsp.ParameterInformation, len(parameters))
+	for i := range parameters {
+		info.Parameters[i] = lsp.ParameterInformation{Label: parameters[i]}
+	}
+	return info
+}
+
 // ident looks for first ident node in the given path.
 // handles idents and selectors
 func ident(prog *loader.Program, pkg *loader.PackageInfo, node ast.Node) *ast.Ident {
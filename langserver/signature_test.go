@@ -0,0 +1,172 @@
+package langserver
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"golang.org/x/tools/go/loader"
+)
+
+const signatureTestSrc = `package p
+
+type Fooer interface {
+	Foo(x, y int) string
+}
+
+type A struct{}
+
+func (A) Foo(x, y int) string { return "a" }
+
+type B struct{}
+
+func (B) Foo(x, y int) string { return "b" }
+
+func Sum(nums ...int) int {
+	total := 0
+	for _, n := range nums {
+		total += n
+	}
+	return total
+}
+
+func Outer(x int) int { return x }
+
+func Use(f Fooer, a A) {
+	Outer(Sum(1, 2, 3))
+	a.Foo(1, 2)
+	f.Foo(1, 2)
+	_ = make([]int, 0)
+}
+`
+
+func loadSignatureTestProgram(t *testing.T) (*token.FileSet, *loader.Program, *loader.PackageInfo, *ast.File) {
+	t.Helper()
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", signatureTestSrc, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	var conf loader.Config
+	conf.Fset = fset
+	conf.CreateFromFiles("p", f)
+	prog, err := conf.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return fset, prog, prog.Created[0], f
+}
+
+// findCallExprContaining returns the innermost *ast.CallExpr whose Fun,
+// printed via nodeAsString, is funName - used to locate e.g. the "Sum(...)"
+// call nested inside "Outer(Sum(...))".
+func findCallExprContaining(fset *token.FileSet, f *ast.File, funName string) *ast.CallExpr {
+	var found *ast.CallExpr
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if nodeAsString(call.Fun, fset) == funName {
+			found = call
+		}
+		return true
+	})
+	return found
+}
+
+func TestSignaturesForCall_NestedCalls(t *testing.T) {
+	fset, prog, pkg, f := loadSignatureTestProgram(t)
+
+	inner := findCallExprContaining(fset, f, "Sum")
+	if inner == nil {
+		t.Fatal("could not find Sum(...) call")
+	}
+	signatures, _ := signaturesForCall(prog, pkg, inner)
+	if len(signatures) != 1 {
+		t.Fatalf("signaturesForCall(Sum) returned %d signatures, want 1", len(signatures))
+	}
+
+	outer := findCallExprContaining(fset, f, "Outer")
+	if outer == nil {
+		t.Fatal("could not find Outer(...) call")
+	}
+	signatures, _ = signaturesForCall(prog, pkg, outer)
+	if len(signatures) != 1 {
+		t.Fatalf("signaturesForCall(Outer) returned %d signatures, want 1", len(signatures))
+	}
+}
+
+func TestActiveParameterForCall_VariadicOverflow(t *testing.T) {
+	fset, prog, pkg, f := loadSignatureTestProgram(t)
+	call := findCallExprContaining(fset, f, "Sum")
+	if call == nil {
+		t.Fatal("could not find Sum(...) call")
+	}
+
+	// Position the cursor after the third argument, past the variadic
+	// parameter's own index (0).
+	cursor := call.Args[2].End()
+	activeParameter := activeParameterForCall(call, cursor)
+	if activeParameter != 2 {
+		t.Fatalf("activeParameterForCall = %d, want 2 (raw arg count)", activeParameter)
+	}
+
+	variadic, ok := variadicParamIndex(prog, pkg, call)
+	if !ok {
+		t.Fatal("variadicParamIndex reported Sum as non-variadic")
+	}
+	if variadic != 0 {
+		t.Fatalf("variadicParamIndex = %d, want 0", variadic)
+	}
+	if activeParameter > variadic {
+		activeParameter = variadic
+	}
+	if activeParameter != 0 {
+		t.Fatalf("clamped activeParameter = %d, want 0", activeParameter)
+	}
+}
+
+func TestSignaturesForCall_ConcreteMethodCall(t *testing.T) {
+	fset, prog, pkg, f := loadSignatureTestProgram(t)
+	call := findCallExprContaining(fset, f, "a.Foo")
+	if call == nil {
+		t.Fatal("could not find a.Foo(...) call")
+	}
+	signatures, active := signaturesForCall(prog, pkg, call)
+	if len(signatures) != 1 {
+		t.Fatalf("signaturesForCall(a.Foo) returned %d signatures, want 1 (concrete method, not dispatched)", len(signatures))
+	}
+	if active != 0 {
+		t.Fatalf("active signature = %d, want 0", active)
+	}
+}
+
+func TestSignaturesForCall_InterfaceMethodMultiCandidate(t *testing.T) {
+	fset, prog, pkg, f := loadSignatureTestProgram(t)
+	call := findCallExprContaining(fset, f, "f.Foo")
+	if call == nil {
+		t.Fatal("could not find f.Foo(...) call")
+	}
+	signatures, _ := signaturesForCall(prog, pkg, call)
+	// The interface method itself, plus A.Foo and B.Foo.
+	if len(signatures) != 3 {
+		t.Fatalf("signaturesForCall(f.Foo) returned %d signatures, want 3", len(signatures))
+	}
+}
+
+func TestSignaturesForCall_Builtin(t *testing.T) {
+	fset, prog, pkg, f := loadSignatureTestProgram(t)
+	call := findCallExprContaining(fset, f, "make")
+	if call == nil {
+		t.Fatal("could not find make(...) call")
+	}
+	signatures, _ := signaturesForCall(prog, pkg, call)
+	if len(signatures) != 1 {
+		t.Fatalf("signaturesForCall(make) returned %d signatures, want 1", len(signatures))
+	}
+	if signatures[0].Label == "" {
+		t.Error("builtin signature has an empty label")
+	}
+}
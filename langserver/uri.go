@@ -0,0 +1,103 @@
+package langserver
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"unicode"
+)
+
+// DocumentURI is a file URI, mirroring gopls' span.URI: unlike plain
+// "file://"+path concatenation it is RFC 3986 percent-encoded and round-trips
+// Windows drive letters and UNC shares correctly.
+type DocumentURI string
+
+// IsFile reports whether uri uses the file scheme.
+func (uri DocumentURI) IsFile() bool {
+	return strings.HasPrefix(string(uri), "file://")
+}
+
+// Filename returns the local filesystem path uri refers to. It is the
+// inverse of URIFromPath, including always lowercasing a Windows drive
+// letter so that Filename(URIFromPath(p)) is stable regardless of how p
+// capitalized its drive.
+func (uri DocumentURI) Filename() string {
+	path, err := uri.filename()
+	if err != nil {
+		return ""
+	}
+	return filepath.FromSlash(path)
+}
+
+func (uri DocumentURI) filename() (string, error) {
+	u, err := url.ParseRequestURI(string(uri))
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "file" {
+		return "", fmt.Errorf("%s: not a file URI", uri)
+	}
+	path := u.Path
+	if isWindowsDriveURIPath(path) {
+		path = strings.ToLower(path[1:2]) + path[2:]
+	}
+	if u.Host != "" {
+		// UNC share: file://host/share/x -> \\host\share\x
+		return "\\\\" + u.Host + filepath.FromSlash(path), nil
+	}
+	return path, nil
+}
+
+// URIFromPath converts an absolute filesystem path to a DocumentURI,
+// percent-encoding reserved characters and normalizing Windows drive
+// letters (C:\x -> file:///C:/x) and UNC shares (\\host\share\x ->
+// file://host/share/x). UNC shares are a Windows-only concept: a leading
+// "//" is otherwise just a Unix path with a doubled separator, e.g. from
+// joining a root "/" with a path that already started with "/".
+func URIFromPath(path string) DocumentURI {
+	path = filepath.ToSlash(path)
+	u := url.URL{Scheme: "file"}
+	if rest := strings.TrimPrefix(path, "//"); runtime.GOOS == "windows" && rest != path && rest != "" {
+		// UNC share, now slash-separated as "host/share/x".
+		parts := strings.SplitN(rest, "/", 2)
+		u.Host = parts[0]
+		if len(parts) > 1 {
+			u.Path = "/" + parts[1]
+		}
+	} else if isWindowsDrivePath(path) {
+		u.Path = "/" + path
+	} else {
+		u.Path = path
+	}
+	return DocumentURI(u.String())
+}
+
+// isWindowsDrivePath reports whether path begins with a drive letter, e.g.
+// "C:\x" or "C:/x". Only meaningful on Windows: on any other OS, a leading
+// "a:" is a legal Unix filename, not a drive letter, so this always reports
+// false there.
+func isWindowsDrivePath(path string) bool {
+	if runtime.GOOS != "windows" {
+		return false
+	}
+	if len(path) < 4 {
+		return false
+	}
+	return unicode.IsLetter(rune(path[0])) && path[1] == ':'
+}
+
+// isWindowsDriveURIPath reports whether uriPath begins with a percent-
+// decoded drive letter, e.g. "/C:/x". Only meaningful on Windows: on any
+// other OS, "/a:b" is an absolute Unix path to a file named "a:b", not a
+// drive-letter path, so this always reports false there.
+func isWindowsDriveURIPath(uriPath string) bool {
+	if runtime.GOOS != "windows" {
+		return false
+	}
+	if len(uriPath) < 4 || uriPath[0] != '/' {
+		return false
+	}
+	return unicode.IsLetter(rune(uriPath[1])) && uriPath[2] == ':'
+}
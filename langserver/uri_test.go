@@ -0,0 +1,65 @@
+package langserver
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestURIFromPath_RoundTrip(t *testing.T) {
+	tests := []string{
+		"/a/b/c.go",
+		"/a/b c/d.go",
+		"/a/b#c.go",
+		"/a/b%c.go",
+		"/héllo/wörld.go",
+		"/a:b",
+		"/c:foo/bar",
+		"//double/leading/slash.go",
+	}
+	for _, path := range tests {
+		t.Run(path, func(t *testing.T) {
+			got := URIFromPath(path).Filename()
+			if got != path {
+				t.Errorf("URIFromPath(%q).Filename() = %q, want %q", path, got, path)
+			}
+		})
+	}
+}
+
+func TestURIFromPath_IsFile(t *testing.T) {
+	if !URIFromPath("/a/b.go").IsFile() {
+		t.Error("URIFromPath result does not report IsFile()")
+	}
+}
+
+// FuzzURIFromPathRoundTrip checks that URIFromPath().Filename() round-trips
+// arbitrary Unix-style absolute paths, including ones whose first component
+// looks like a Windows drive letter (e.g. "/a:b") but isn't one on this
+// platform, and ones starting with a doubled leading slash (e.g.
+// "//host/share") that on Windows would be a UNC share but on any other OS
+// is just an ordinary absolute path.
+func FuzzURIFromPathRoundTrip(f *testing.F) {
+	for _, seed := range []string{
+		"/a/b/c.go",
+		"/a:b",
+		"/c:foo/bar",
+		"//double/leading/slash.go",
+		"/with space/file.go",
+		"/with#hash/file.go",
+		"/with%percent/file.go",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, rel string) {
+		if strings.ContainsRune(rel, 0) || !utf8.ValidString(rel) {
+			t.Skip("not a realistic path component")
+		}
+		path := "/" + filepath.ToSlash(rel)
+		got := URIFromPath(path).Filename()
+		if got != path {
+			t.Errorf("URIFromPath(%q).Filename() = %q, want %q", path, got, path)
+		}
+	})
+}
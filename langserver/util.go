@@ -3,9 +3,7 @@ package langserver
 import (
 	"fmt"
 	"log"
-	"net/url"
 	"os"
-	"path/filepath"
 	"runtime"
 	"strings"
 )
@@ -39,28 +37,19 @@ func IsVendorDir(dir string) bool {
 
 // isURI tells if s denotes an URI
 func isURI(s string) bool {
-	return strings.HasPrefix(s, "file:///")
+	return DocumentURI(s).IsFile()
 }
 
-// pathToURI converts given absolute path to file URI
+// pathToURI converts given absolute path to file URI. It is a thin wrapper
+// around URIFromPath kept for call sites that only want the string form.
 func pathToURI(path string) string {
-	return "file://" + path
+	return string(URIFromPath(path))
 }
 
-// uriToPath converts given file URI to path
+// uriToPath converts given file URI to path. It is a thin wrapper around
+// DocumentURI.Filename kept for call sites that only have a plain string.
 func uriToPath(uri string) string {
-	comps, _ := url.Parse(uri)
-	path := comps.Path
-	if runtime.GOOS == "windows" {
-		// path would be something like "/d:/go/src/"
-		// didOpen assume return path must start from "/", whereres hover assume return path is valid path.
-		// So I decide to return correct path and modify didOpen
-		// because this function name suggest this is what we should do.
-
-		// remove root / and convert to backslash.
-		return filepath.Clean(path[1:])
-	}
-	return path
+	return DocumentURI(uri).Filename()
 }
 
 // panicf takes the return value of recover() and outputs data to the log with
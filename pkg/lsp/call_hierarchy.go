@@ -0,0 +1,40 @@
+package lsp
+
+// CallHierarchyItem represents a programming construct like a function or a
+// method that can be used as the start or target of a call hierarchy
+// request. See https://microsoft.github.io/language-server-protocol for the
+// textDocument/prepareCallHierarchy family of requests.
+type CallHierarchyItem struct {
+	Name           string     `json:"name"`
+	Kind           SymbolKind `json:"kind"`
+	Detail         string     `json:"detail,omitempty"`
+	URI            string     `json:"uri"`
+	Range          Range      `json:"range"`
+	SelectionRange Range      `json:"selectionRange"`
+}
+
+// CallHierarchyIncomingCallsParams is the params sent with a
+// callHierarchy/incomingCalls request.
+type CallHierarchyIncomingCallsParams struct {
+	Item CallHierarchyItem `json:"item"`
+}
+
+// CallHierarchyIncomingCall describes a call from CallHierarchyIncomingCall.From
+// to the item that was used as the request's starting point.
+type CallHierarchyIncomingCall struct {
+	From       CallHierarchyItem `json:"from"`
+	FromRanges []Range           `json:"fromRanges"`
+}
+
+// CallHierarchyOutgoingCallsParams is the params sent with a
+// callHierarchy/outgoingCalls request.
+type CallHierarchyOutgoingCallsParams struct {
+	Item CallHierarchyItem `json:"item"`
+}
+
+// CallHierarchyOutgoingCall describes a call from the item that was used as
+// the request's starting point to CallHierarchyOutgoingCall.To.
+type CallHierarchyOutgoingCall struct {
+	To         CallHierarchyItem `json:"to"`
+	FromRanges []Range           `json:"fromRanges"`
+}